@@ -0,0 +1,117 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pennsieve/processor-test-llm-summarizer/internal/retry"
+	"github.com/pennsieve/processor-test-llm-summarizer/shard"
+	"github.com/pennsieve/processor-test-llm-summarizer/summarizer"
+)
+
+// defaultShardThresholdBytes is the base64-encoded document size above
+// which runProcessor switches from a single Summarize call to map-reduce
+// summarization across shards.
+const defaultShardThresholdBytes = 200 * 1024
+
+// defaultShardConcurrency bounds how many shard summaries run at once so a
+// single large file doesn't serialize the whole batch, while still
+// respecting the governor's own rate limits.
+const defaultShardConcurrency = 4
+
+// shardConfig holds the tunables for splitting oversized input documents,
+// mirroring the shardSize-style env var pattern used elsewhere for
+// configuring batch sizes.
+type shardConfig struct {
+	ThresholdBytes int
+	ShardSize      int
+	Concurrency    int
+}
+
+func shardConfigFromEnv() shardConfig {
+	return shardConfig{
+		ThresholdBytes: envInt("SHARD_THRESHOLD_BYTES", defaultShardThresholdBytes),
+		ShardSize:      envInt("SHARD_SIZE", shard.DefaultShardSize),
+		Concurrency:    envInt("SHARD_CONCURRENCY", defaultShardConcurrency),
+	}
+}
+
+// retryConfigFromEnv builds the retry.Config governing gov.Invoke calls from
+// GOV_INVOKE_MAX_ATTEMPTS (attempt budget), GOV_INVOKE_RETRY_BACKOFF (base
+// delay before exponential backoff and jitter), and GOV_INVOKE_TIMEOUT
+// (per-attempt timeout), falling back to retry's defaults when unset.
+func retryConfigFromEnv() retry.Config {
+	return retry.Config{
+		MaxAttempts: envInt("GOV_INVOKE_MAX_ATTEMPTS", retry.DefaultMaxAttempts),
+		BaseDelay:   envDuration("GOV_INVOKE_RETRY_BACKOFF", retry.DefaultBaseDelay),
+		MaxDelay:    retry.DefaultMaxDelay,
+		Timeout:     envDuration("GOV_INVOKE_TIMEOUT", retry.DefaultTimeout),
+	}
+}
+
+// summarizerConfigFromEnv selects the Summarizer backend and model via
+// SUMMARIZER_BACKEND / SUMMARIZER_MODEL, defaulting to the Pennsieve
+// governor when unset.
+func summarizerConfigFromEnv() summarizer.Config {
+	return summarizer.Config{
+		Backend: summarizer.Backend(os.Getenv("SUMMARIZER_BACKEND")),
+		Model:   os.Getenv("SUMMARIZER_MODEL"),
+		Retry:   retryConfigFromEnv(),
+	}
+}
+
+func envInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("Invalid value for %s=%q, using default %d", key, v, def)
+		return def
+	}
+	return n
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("Invalid value for %s=%q, using default %s", key, v, def)
+		return def
+	}
+	return d
+}
+
+// includePatternsFromEnv returns the comma-separated glob patterns in
+// INCLUDE_PATTERNS, or def if the env var is unset.
+func includePatternsFromEnv(def []string) []string {
+	return envPatternList("INCLUDE_PATTERNS", def)
+}
+
+// excludePatternsFromEnv returns the comma-separated glob patterns in
+// EXCLUDE_PATTERNS, or def if the env var is unset.
+func excludePatternsFromEnv(def []string) []string {
+	return envPatternList("EXCLUDE_PATTERNS", def)
+}
+
+func envPatternList(key string, def []string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	parts := strings.Split(v, ",")
+	patterns := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// defaultIncludePatterns are the doublestar-style glob patterns discovery
+// uses when INCLUDE_PATTERNS isn't set, one per format formats.Decode
+// supports, so input discovery recurses into dataset subdirectories instead
+// of only looking at the top level of inputDir.
+var defaultIncludePatterns = []string{
+	"**/*.json",
+	"**/*.yaml",
+	"**/*.yml",
+	"**/*.toml",
+	"**/*.ndjson",
+	"**/*.csv",
+}
+
+// defaultExcludePatterns keeps discovery out of VCS metadata and installed
+// dependency trees that sometimes end up inside a dataset directory.
+var defaultExcludePatterns = []string{
+	"**/.git/**",
+	"**/node_modules/**",
+}
+
+// discoveredFile is one input file found by discoverFiles, carrying both
+// its absolute path (for opening) and its path relative to inputDir (for
+// mirroring the dataset's directory structure under outputDir).
+type discoveredFile struct {
+	AbsPath string
+	RelPath string
+}
+
+// discoverFiles walks inputDir and returns every file whose slash-separated
+// relative path matches at least one include pattern and no exclude
+// pattern, sorted by relative path for deterministic processing order.
+// Patterns use doublestar glob syntax: "**" matches any number of path
+// segments, "*" and "?" match within a single segment. A directory whose
+// relative path matches an exclude pattern's "/**" prefix is pruned from
+// the walk entirely, rather than merely filtered out afterward.
+//
+// If zero files match, discoverFiles returns a descriptive error naming
+// inputDir and both pattern lists rather than calling log.Fatal itself, so
+// the caller decides how to report it.
+func discoverFiles(inputDir string, includes, excludes []string) ([]discoveredFile, error) {
+	includeMatchers, err := compileGlobs(includes)
+	if err != nil {
+		return nil, fmt.Errorf("compile include patterns: %w", err)
+	}
+	excludeMatchers, err := compileGlobs(excludes)
+	if err != nil {
+		return nil, fmt.Errorf("compile exclude patterns: %w", err)
+	}
+
+	var files []discoveredFile
+	err = filepath.WalkDir(inputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == inputDir {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(inputDir, path)
+		if err != nil {
+			return fmt.Errorf("relative path for %s: %w", path, err)
+		}
+		relSlash := filepath.ToSlash(relPath)
+
+		if d.IsDir() {
+			if anyDirMatch(excludeMatchers, relSlash) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !anyMatch(includeMatchers, relSlash) || anyMatch(excludeMatchers, relSlash) {
+			return nil
+		}
+
+		files = append(files, discoveredFile{AbsPath: path, RelPath: relPath})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk %s: %w", inputDir, err)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].RelPath < files[j].RelPath })
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no input files found under %s matching include patterns %v (excluding %v)", inputDir, includes, excludes)
+	}
+	return files, nil
+}
+
+// globMatcher pairs a glob pattern's compiled file-matching regex with, for
+// patterns ending in "/**", a second regex matching the directory prefix
+// itself, so discoverFiles can prune that whole subtree during the walk
+// instead of descending into it only to filter every file back out.
+type globMatcher struct {
+	file *regexp.Regexp
+	dir  *regexp.Regexp
+}
+
+func compileGlobs(patterns []string) ([]globMatcher, error) {
+	matchers := make([]globMatcher, 0, len(patterns))
+	for _, p := range patterns {
+		fileRe, err := globToRegexp(p)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", p, err)
+		}
+		m := globMatcher{file: fileRe}
+		if strings.HasSuffix(p, "/**") {
+			dirRe, err := globToRegexp(strings.TrimSuffix(p, "/**"))
+			if err != nil {
+				return nil, fmt.Errorf("pattern %q: %w", p, err)
+			}
+			m.dir = dirRe
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+func anyMatch(matchers []globMatcher, relSlash string) bool {
+	for _, m := range matchers {
+		if m.file.MatchString(relSlash) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyDirMatch(matchers []globMatcher, relSlash string) bool {
+	for _, m := range matchers {
+		if m.dir != nil && m.dir.MatchString(relSlash) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegexp translates a doublestar glob pattern into an anchored
+// regular expression: "**/" matches any number of leading path segments,
+// including none, a lone "**" matches anything, "*" matches within a single
+// path segment, and "?" matches a single non-separator character.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteByte('^')
+	for i := 0; i < len(pattern); {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 3
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i += 2
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+			i++
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+			i++
+		default:
+			b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+			i++
+		}
+	}
+	b.WriteByte('$')
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// writeFiles creates each path (relative to dir) with empty content,
+// creating parent directories as needed, and returns dir.
+func writeFiles(t *testing.T, dir string, paths ...string) string {
+	t.Helper()
+	for _, p := range paths {
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", full, err)
+		}
+		if err := os.WriteFile(full, []byte("x"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", full, err)
+		}
+	}
+	return dir
+}
+
+func relPaths(files []discoveredFile) []string {
+	out := make([]string, len(files))
+	for i, f := range files {
+		out[i] = filepath.ToSlash(f.RelPath)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestDiscoverFilesTopLevelAndNested(t *testing.T) {
+	dir := writeFiles(t, t.TempDir(),
+		"a.json",
+		"nested/b.json",
+		"nested/deeper/c.json",
+		"skip.txt",
+	)
+
+	files, err := discoverFiles(dir, []string{"**/*.json"}, nil)
+	if err != nil {
+		t.Fatalf("discoverFiles: %v", err)
+	}
+
+	got := relPaths(files)
+	want := []string{"a.json", "nested/b.json", "nested/deeper/c.json"}
+	if !equalStrings(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestDiscoverFilesExcludePrunesDescent(t *testing.T) {
+	dir := writeFiles(t, t.TempDir(),
+		"keep.json",
+		".git/HEAD",
+		".git/objects/pack/pack-abc.json",
+	)
+
+	files, err := discoverFiles(dir, []string{"**/*.json", "**/HEAD"}, []string{"**/.git/**"})
+	if err != nil {
+		t.Fatalf("discoverFiles: %v", err)
+	}
+
+	got := relPaths(files)
+	want := []string{"keep.json"}
+	if !equalStrings(got, want) {
+		t.Fatalf("got %v, want %v (the .git subtree should have been pruned, not just filtered)", got, want)
+	}
+}
+
+// TestDiscoverFilesExcludeDirPruned plants a broken symlink under an
+// excluded directory and asserts that discovery neither returns it nor
+// fails on it. WalkDir does not follow or stat symlinks, so the result is
+// the same whether the directory is pruned or its contents are filtered.
+func TestDiscoverFilesExcludeDirPruned(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "keep.json")
+
+	excludedDir := filepath.Join(dir, "node_modules", "pkg")
+	if err := os.MkdirAll(excludedDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	brokenLink := filepath.Join(excludedDir, "broken.json")
+	if err := os.Symlink(filepath.Join(dir, "does-not-exist"), brokenLink); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	files, err := discoverFiles(dir, []string{"**/*.json"}, []string{"**/node_modules/**"})
+	if err != nil {
+		t.Fatalf("discoverFiles: %v", err)
+	}
+
+	got := relPaths(files)
+	want := []string{"keep.json"}
+	if !equalStrings(got, want) {
+		t.Fatalf("got %v, want %v (broken symlink under excluded dir must not be returned)", got, want)
+	}
+}
+
+func TestDiscoverFilesPatternWithoutDirSuffix(t *testing.T) {
+	dir := writeFiles(t, t.TempDir(),
+		"top.csv",
+		"nested/inner.csv",
+	)
+
+	files, err := discoverFiles(dir, []string{"*.csv"}, nil)
+	if err != nil {
+		t.Fatalf("discoverFiles: %v", err)
+	}
+
+	got := relPaths(files)
+	want := []string{"top.csv"}
+	if !equalStrings(got, want) {
+		t.Fatalf("got %v, want %v (a pattern with no \"**\" should only match the top level)", got, want)
+	}
+}
+
+func TestDiscoverFilesNoMatchesReturnsDescriptiveError(t *testing.T) {
+	dir := writeFiles(t, t.TempDir(), "data.txt")
+
+	includes := []string{"**/*.json", "**/*.csv"}
+	excludes := []string{"**/.git/**"}
+	_, err := discoverFiles(dir, includes, excludes)
+	if err == nil {
+		t.Fatal("expected an error when zero files match")
+	}
+
+	msg := err.Error()
+	for _, p := range includes {
+		if !strings.Contains(msg, p) {
+			t.Errorf("error %q does not mention include pattern %q", msg, p)
+		}
+	}
+	for _, p := range excludes {
+		if !strings.Contains(msg, p) {
+			t.Errorf("error %q does not mention exclude pattern %q", msg, p)
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
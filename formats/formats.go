@@ -0,0 +1,260 @@
+// Package formats provides a unified decoding layer for the input file
+// formats the summarizer processor accepts. Each decoder normalizes its
+// input to a value that can be re-encoded as canonical JSON before being
+// handed to the LLM as a document block.
+package formats
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// MimeType identifies the source shape of a decoded document so it can be
+// surfaced to the LLM prompt (e.g. "this document was a CSV file").
+type MimeType string
+
+const (
+	MimeJSON   MimeType = "json"
+	MimeYAML   MimeType = "yaml"
+	MimeTOML   MimeType = "toml"
+	MimeNDJSON MimeType = "ndjson"
+	MimeCSV    MimeType = "csv"
+)
+
+// ExtMime maps a lowercase file extension, without the leading dot, to the
+// MimeType used to select a decoder.
+var ExtMime = map[string]MimeType{
+	"json":   MimeJSON,
+	"yaml":   MimeYAML,
+	"yml":    MimeYAML,
+	"toml":   MimeTOML,
+	"ndjson": MimeNDJSON,
+	"csv":    MimeCSV,
+}
+
+// csvSampleSize is the number of data rows included verbatim in a CSVSchema.
+const csvSampleSize = 5
+
+// CSVSchema is the canonical representation produced for CSV input. Rather
+// than carrying every row as raw text, it captures enough shape information
+// for the LLM to reason about the dataset without re-deriving it itself.
+type CSVSchema struct {
+	Columns    []CSVColumn `json:"columns"`
+	RowCount   int         `json:"rowCount"`
+	SampleRows [][]string  `json:"sampleRows"`
+}
+
+// CSVColumn describes one inferred column of a CSVSchema.
+type CSVColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Decode reads r and parses it according to ext (a file extension without
+// the leading dot, e.g. "csv"), returning a value that can be passed to
+// json.Marshal to obtain the canonical representation of the document.
+func Decode(r io.Reader, ext string) (any, error) {
+	mime, ok := ExtMime[strings.ToLower(ext)]
+	if !ok {
+		return nil, fmt.Errorf("formats: unsupported extension %q", ext)
+	}
+
+	switch mime {
+	case MimeJSON:
+		return decodeJSON(r)
+	case MimeYAML:
+		return decodeYAML(r)
+	case MimeTOML:
+		return decodeTOML(r)
+	case MimeNDJSON:
+		return decodeNDJSON(r)
+	case MimeCSV:
+		return decodeCSV(r)
+	default:
+		return nil, fmt.Errorf("formats: unsupported extension %q", ext)
+	}
+}
+
+func decodeJSON(r io.Reader) (any, error) {
+	var v any
+	if err := json.NewDecoder(r).Decode(&v); err != nil {
+		return nil, fmt.Errorf("formats: decode json: %w", err)
+	}
+	return v, nil
+}
+
+func decodeYAML(r io.Reader) (any, error) {
+	var v any
+	if err := yaml.NewDecoder(r).Decode(&v); err != nil {
+		return nil, fmt.Errorf("formats: decode yaml: %w", err)
+	}
+	return normalizeYAML(v), nil
+}
+
+// normalizeYAML walks a value decoded by yaml.v3 so that its maps and
+// slices match what encoding/json expects, since yaml.v3 produces
+// map[interface{}]interface{} for a mapping whose keys are not all strings;
+// such keys are converted to their fmt.Sprint form.
+func normalizeYAML(v any) any {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			out[k] = normalizeYAML(val)
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			out[fmt.Sprint(k)] = normalizeYAML(val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, val := range t {
+			out[i] = normalizeYAML(val)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func decodeTOML(r io.Reader) (any, error) {
+	var v map[string]interface{}
+	if _, err := toml.NewDecoder(r).Decode(&v); err != nil {
+		return nil, fmt.Errorf("formats: decode toml: %w", err)
+	}
+	return v, nil
+}
+
+func decodeNDJSON(r io.Reader) (any, error) {
+	var out []interface{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(line), &v); err != nil {
+			return nil, fmt.Errorf("formats: decode ndjson line: %w", err)
+		}
+		out = append(out, v)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("formats: scan ndjson: %w", err)
+	}
+	return out, nil
+}
+
+func decodeCSV(r io.Reader) (any, error) {
+	header, rows, err := ReadCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	return SummarizeCSV(header, rows), nil
+}
+
+// ReadCSV parses r as CSV and splits the result into its header row and
+// data rows. It is exported so that callers sharding an oversized CSV file
+// by row range can re-chunk the raw rows before each chunk is summarized
+// with SummarizeCSV.
+func ReadCSV(r io.Reader) (header []string, rows [][]string, err error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("formats: decode csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, nil
+	}
+	return records[0], records[1:], nil
+}
+
+// SummarizeCSV builds the schema-aware CSVSchema for a header and its data
+// rows. Column types are inferred from the rows passed in, so calling this
+// on a row-range shard describes only that shard's rows.
+func SummarizeCSV(header []string, rows [][]string) CSVSchema {
+	columns := make([]CSVColumn, len(header))
+	for i, name := range header {
+		columns[i] = CSVColumn{Name: name, Type: inferColumnType(rows, i)}
+	}
+
+	sampleCount := csvSampleSize
+	if sampleCount > len(rows) {
+		sampleCount = len(rows)
+	}
+
+	return CSVSchema{
+		Columns:    columns,
+		RowCount:   len(rows),
+		SampleRows: rows[:sampleCount],
+	}
+}
+
+// inferColumnType classifies a CSV column as "int", "float", "bool" or
+// "string" based on every value observed in that column. Any non-numeric,
+// non-boolean value widens the column to "string", and so does a mix of
+// numeric and boolean values (e.g. a 0/1 flag column with a stray "true"),
+// since neither type alone describes every value actually present.
+func inferColumnType(rows [][]string, col int) string {
+	sawInt, sawFloat, sawBool := false, false, false
+	for _, row := range rows {
+		if col >= len(row) {
+			continue
+		}
+		v := strings.TrimSpace(row[col])
+		if v == "" {
+			continue
+		}
+		switch {
+		case isInt(v):
+			sawInt = true
+		case isFloat(v):
+			sawFloat = true
+		case isBool(v):
+			sawBool = true
+		default:
+			return "string"
+		}
+	}
+	if (sawInt || sawFloat) && sawBool {
+		return "string"
+	}
+	switch {
+	case sawFloat:
+		return "float"
+	case sawInt:
+		return "int"
+	case sawBool:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+func isInt(v string) bool {
+	_, err := strconv.ParseInt(v, 10, 64)
+	return err == nil
+}
+
+func isFloat(v string) bool {
+	_, err := strconv.ParseFloat(v, 64)
+	return err == nil
+}
+
+func isBool(v string) bool {
+	_, err := strconv.ParseBool(v)
+	return err == nil
+}
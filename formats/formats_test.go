@@ -0,0 +1,100 @@
+package formats
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDecodeDispatchesOnExtension(t *testing.T) {
+	cases := []struct {
+		ext  string
+		body string
+		want any
+	}{
+		{"json", `{"a":1}`, map[string]interface{}{"a": float64(1)}},
+		{"yaml", "a: 1\n", map[string]interface{}{"a": 1}},
+		{"ndjson", "{\"a\":1}\n{\"a\":2}\n", []interface{}{map[string]interface{}{"a": float64(1)}, map[string]interface{}{"a": float64(2)}}},
+	}
+	for _, tc := range cases {
+		v, err := Decode(strings.NewReader(tc.body), tc.ext)
+		if err != nil {
+			t.Fatalf("Decode(%q): %v", tc.ext, err)
+		}
+		got, _ := json.Marshal(v)
+		want, _ := json.Marshal(tc.want)
+		if string(got) != string(want) {
+			t.Errorf("Decode(%q) = %s, want %s", tc.ext, got, want)
+		}
+	}
+}
+
+func TestDecodeUnsupportedExtension(t *testing.T) {
+	if _, err := Decode(strings.NewReader("x"), "exe"); err == nil {
+		t.Fatal("expected an error for an unsupported extension")
+	}
+}
+
+func TestDecodeTOML(t *testing.T) {
+	v, err := Decode(strings.NewReader("name = \"ds\"\ncount = 3\n"), "toml")
+	if err != nil {
+		t.Fatalf("Decode(toml): %v", err)
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		t.Fatalf("Decode(toml) = %T, want map[string]interface{}", v)
+	}
+	if m["name"] != "ds" {
+		t.Errorf("name = %v, want %q", m["name"], "ds")
+	}
+}
+
+func TestDecodeCSVProducesSchema(t *testing.T) {
+	body := "id,active\n1,true\n2,false\n"
+	v, err := Decode(strings.NewReader(body), "csv")
+	if err != nil {
+		t.Fatalf("Decode(csv): %v", err)
+	}
+	schema, ok := v.(CSVSchema)
+	if !ok {
+		t.Fatalf("Decode(csv) = %T, want CSVSchema", v)
+	}
+	if schema.RowCount != 2 {
+		t.Errorf("RowCount = %d, want 2", schema.RowCount)
+	}
+	if len(schema.Columns) != 2 || schema.Columns[0].Name != "id" || schema.Columns[1].Name != "active" {
+		t.Errorf("Columns = %+v", schema.Columns)
+	}
+}
+
+func TestInferColumnType(t *testing.T) {
+	cases := []struct {
+		name string
+		rows [][]string
+		want string
+	}{
+		{"all int", [][]string{{"1"}, {"2"}, {"3"}}, "int"},
+		{"all float", [][]string{{"1.5"}, {"2"}}, "float"},
+		{"all bool", [][]string{{"true"}, {"false"}}, "bool"},
+		{"mixed numeric and string", [][]string{{"1"}, {"abc"}}, "string"},
+		{"blank values ignored", [][]string{{""}, {"1"}, {""}}, "int"},
+		{
+			name: "int column with a stray bool is ambiguous, not int",
+			rows: [][]string{{"0"}, {"1"}, {"true"}},
+			want: "string",
+		},
+		{
+			name: "float column with a stray bool is ambiguous, not float",
+			rows: [][]string{{"0.5"}, {"true"}},
+			want: "string",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := inferColumnType(tc.rows, 0)
+			if got != tc.want {
+				t.Errorf("inferColumnType(%v) = %q, want %q", tc.rows, got, tc.want)
+			}
+		})
+	}
+}
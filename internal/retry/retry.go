@@ -0,0 +1,111 @@
+// Package retry wraps a fallible call with exponential backoff, jitter,
+// and a bounded attempt count, so a transient failure doesn't abort an
+// entire run.
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+const (
+	// DefaultMaxAttempts is how many times Do will call fn before giving up.
+	DefaultMaxAttempts = 3
+	// DefaultBaseDelay is the backoff delay before the second attempt.
+	DefaultBaseDelay = 500 * time.Millisecond
+	// DefaultMaxDelay caps the backoff delay regardless of attempt count.
+	DefaultMaxDelay = 10 * time.Second
+	// DefaultTimeout bounds how long a single attempt may run.
+	DefaultTimeout = 60 * time.Second
+)
+
+// Config controls Do's retry behavior. A zero Config is replaced field by
+// field with the package defaults.
+type Config struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Timeout     time.Duration
+}
+
+// DefaultConfig returns the package's default retry behavior.
+func DefaultConfig() Config {
+	return Config{
+		MaxAttempts: DefaultMaxAttempts,
+		BaseDelay:   DefaultBaseDelay,
+		MaxDelay:    DefaultMaxDelay,
+		Timeout:     DefaultTimeout,
+	}
+}
+
+func (c Config) withDefaults() Config {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = DefaultMaxAttempts
+	}
+	if c.BaseDelay <= 0 {
+		c.BaseDelay = DefaultBaseDelay
+	}
+	if c.MaxDelay <= 0 {
+		c.MaxDelay = DefaultMaxDelay
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	return c
+}
+
+// IsFatal reports whether err should abort retrying immediately instead of
+// being retried.
+type IsFatal func(err error) bool
+
+// Result reports how many attempts Do made, so a caller can surface retry
+// counts alongside the outcome.
+type Result struct {
+	Attempts int
+}
+
+// Do calls fn, retrying with exponential backoff and jitter until it
+// succeeds, isFatal reports the error as non-retryable, cfg.MaxAttempts is
+// reached, or ctx is done. Each attempt gets its own cfg.Timeout deadline
+// derived from ctx.
+func Do(ctx context.Context, cfg Config, isFatal IsFatal, fn func(ctx context.Context) error) (Result, error) {
+	cfg = cfg.withDefaults()
+
+	var lastErr error
+	for attempt := 1; attempt <= cfg.MaxAttempts; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+		err := fn(attemptCtx)
+		cancel()
+
+		if err == nil {
+			return Result{Attempts: attempt}, nil
+		}
+
+		lastErr = err
+		if isFatal != nil && isFatal(err) {
+			return Result{Attempts: attempt}, err
+		}
+		if attempt == cfg.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-time.After(backoff(cfg.BaseDelay, cfg.MaxDelay, attempt)):
+		case <-ctx.Done():
+			return Result{Attempts: attempt}, ctx.Err()
+		}
+	}
+	return Result{Attempts: cfg.MaxAttempts}, lastErr
+}
+
+// backoff computes an exponential delay for the given 1-indexed attempt,
+// capped at maxDelay, with up to 50% jitter to avoid synchronized retries
+// across concurrently running shards.
+func backoff(base, maxDelay time.Duration, attempt int) time.Duration {
+	d := base * time.Duration(int64(1)<<uint(attempt-1))
+	if d <= 0 || d > maxDelay {
+		d = maxDelay
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d)/2+1))
+}
@@ -0,0 +1,106 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errFatal = errors.New("fatal")
+var errTransient = errors.New("transient")
+
+func testConfig() Config {
+	return Config{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+		Timeout:     time.Second,
+	}
+}
+
+func TestDoFatalErrorShortCircuits(t *testing.T) {
+	calls := 0
+	isFatal := func(err error) bool { return errors.Is(err, errFatal) }
+
+	res, err := Do(context.Background(), testConfig(), isFatal, func(ctx context.Context) error {
+		calls++
+		return errFatal
+	})
+
+	if !errors.Is(err, errFatal) {
+		t.Fatalf("err = %v, want errFatal", err)
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1 (fatal error must not be retried)", calls)
+	}
+	if res.Attempts != 1 {
+		t.Fatalf("Attempts = %d, want 1", res.Attempts)
+	}
+}
+
+func TestDoExhaustsMaxAttempts(t *testing.T) {
+	calls := 0
+	isFatal := func(err error) bool { return errors.Is(err, errFatal) }
+
+	cfg := testConfig()
+	res, err := Do(context.Background(), cfg, isFatal, func(ctx context.Context) error {
+		calls++
+		return errTransient
+	})
+
+	if !errors.Is(err, errTransient) {
+		t.Fatalf("err = %v, want errTransient", err)
+	}
+	if calls != cfg.MaxAttempts {
+		t.Fatalf("calls = %d, want %d", calls, cfg.MaxAttempts)
+	}
+	if res.Attempts != cfg.MaxAttempts {
+		t.Fatalf("Attempts = %d, want %d", res.Attempts, cfg.MaxAttempts)
+	}
+}
+
+func TestDoSucceedsAfterTransientErrors(t *testing.T) {
+	calls := 0
+	res, err := Do(context.Background(), testConfig(), nil, func(ctx context.Context) error {
+		calls++
+		if calls < 2 {
+			return errTransient
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+	if res.Attempts != 2 {
+		t.Fatalf("Attempts = %d, want 2", res.Attempts)
+	}
+}
+
+func TestDoReturnsOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	cfg := testConfig()
+	cfg.BaseDelay = 50 * time.Millisecond
+	cfg.MaxDelay = 50 * time.Millisecond
+
+	res, err := Do(ctx, cfg, nil, func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			cancel()
+		}
+		return errTransient
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1 (should stop waiting on the backoff once ctx is done)", calls)
+	}
+	if res.Attempts != 1 {
+		t.Fatalf("Attempts = %d, want 1", res.Attempts)
+	}
+}
@@ -2,8 +2,7 @@ package main
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -13,7 +12,9 @@ import (
 
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/go-pdf/fpdf"
-	"github.com/pennsieve/pennsieve-go-llm/llm"
+	"github.com/pennsieve/processor-test-llm-summarizer/formats"
+	"github.com/pennsieve/processor-test-llm-summarizer/prompt"
+	"github.com/pennsieve/processor-test-llm-summarizer/summarizer"
 )
 
 // LambdaEvent is the payload sent by the Step Functions orchestrator
@@ -27,7 +28,13 @@ type LambdaEvent struct {
 	RefreshToken   string `json:"refreshToken"`
 }
 
+// promptTemplateFlag is the -prompt-template flag, read once at startup and
+// consulted only when PROMPT_TEMPLATE isn't already set in the environment.
+var promptTemplateFlag = flag.String("prompt-template", "", "name of a built-in prompt template (e.g. dataset-summary, schema-only) or a path to a text/template file; overrides the hardcoded prompt (default: use PROMPT_TEMPLATE env var, or the hardcoded prompt if unset)")
+
 func main() {
+	flag.Parse()
+
 	// Detect Lambda runtime: AWS sets _LAMBDA_SERVER_PORT or AWS_LAMBDA_RUNTIME_API
 	if os.Getenv("AWS_LAMBDA_RUNTIME_API") != "" {
 		log.Println("Running as Lambda function")
@@ -50,6 +57,16 @@ func handleLambda(ctx context.Context, event LambdaEvent) error {
 	return nil
 }
 
+// promptTemplateRef resolves the configured prompt template reference,
+// preferring the PROMPT_TEMPLATE env var (so Lambda invocations, which never
+// see the CLI flag, can still opt in) and falling back to -prompt-template.
+func promptTemplateRef() string {
+	if ref := os.Getenv("PROMPT_TEMPLATE"); ref != "" {
+		return ref
+	}
+	return *promptTemplateFlag
+}
+
 func runProcessor(inputDir, outputDir, executionRunID string) {
 	log.Println("LLM Summarizer Processor starting")
 
@@ -60,90 +77,86 @@ func runProcessor(inputDir, outputDir, executionRunID string) {
 	log.Printf("Input directory: %s", inputDir)
 	log.Printf("Output directory: %s", outputDir)
 
-	// Initialize the LLM governor client
-	gov := llm.NewGovernor()
-	if !gov.Available() {
-		log.Fatal("LLM Governor not available: LLM_GOVERNOR_FUNCTION is not set")
+	// Initialize the configured summarizer backend (the Pennsieve governor
+	// by default; see SUMMARIZER_BACKEND)
+	sz, err := summarizer.New(summarizerConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Failed to initialize summarizer: %v", err)
+	}
+	if av, ok := sz.(summarizer.Availabler); ok && !av.Available() {
+		log.Fatal("Summarizer backend not available: LLM_GOVERNOR_FUNCTION is not set")
 	}
 
+	tmpl, err := prompt.Resolve(promptTemplateRef())
+	if err != nil {
+		log.Fatalf("Failed to resolve prompt template: %v", err)
+	}
+	pc := promptConfig{Template: tmpl, ExecutionRunID: executionRunID}
+
 	ctx := context.Background()
 
-	// Find JSON files in the input directory
-	jsonFiles, err := filepath.Glob(filepath.Join(inputDir, "*.json"))
+	// Find input files across every supported format, recursing into
+	// dataset subdirectories (see INCLUDE_PATTERNS / EXCLUDE_PATTERNS)
+	includes := includePatternsFromEnv(defaultIncludePatterns)
+	excludes := excludePatternsFromEnv(defaultExcludePatterns)
+	inputFiles, err := discoverFiles(inputDir, includes, excludes)
 	if err != nil {
-		log.Fatalf("Failed to list JSON files: %v", err)
+		log.Fatalf("Failed to list input files: %v", err)
 	}
-	if len(jsonFiles) == 0 {
-		log.Fatal("No JSON files found in input directory")
+
+	log.Printf("Found %d input file(s):", len(inputFiles))
+	for _, f := range inputFiles {
+		log.Printf("  %s", f.RelPath)
 	}
 
-	log.Printf("Found %d JSON file(s)", len(jsonFiles))
+	cfg := shardConfigFromEnv()
 
-	for _, jsonFile := range jsonFiles {
-		log.Printf("Processing: %s", filepath.Base(jsonFile))
+	var reports []fileReport
+	var failed int
 
-		// Read the JSON file
-		data, err := os.ReadFile(jsonFile)
-		if err != nil {
-			log.Fatalf("Failed to read %s: %v", jsonFile, err)
-		}
+	for _, inputFile := range inputFiles {
+		log.Printf("Processing: %s", inputFile.RelPath)
 
-		// Validate JSON
-		var parsed interface{}
-		if err := json.Unmarshal(data, &parsed); err != nil {
-			log.Fatalf("Invalid JSON in %s: %v", jsonFile, err)
-		}
+		ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(inputFile.AbsPath), "."))
+		mime := formats.ExtMime[ext]
 
-		// Send JSON as a document block to Bedrock via the governor
-		log.Println("Sending to LLM for summarization...")
-
-		docName := strings.TrimSuffix(filepath.Base(jsonFile), filepath.Ext(jsonFile))
-		docB64 := base64.StdEncoding.EncodeToString(data)
-
-		prompt := `The attached JSON document represents a dataset. Please provide a comprehensive summary that includes:
-
-1. Overview: What this dataset contains and its purpose
-2. Structure: The key fields and their types
-3. Content Summary: A description of the data values and any patterns
-4. Potential Uses: What this dataset could be used for`
-
-		resp, err := gov.Invoke(ctx, &llm.InvokeRequest{
-			Model:     llm.ModelHaiku45,
-			System:    "You are a data analyst. Summarize datasets clearly and concisely. Use plain text paragraphs, not markdown.",
-			MaxTokens: 2048,
-			Messages: []llm.Message{
-				llm.UserMessage(
-					llm.DocumentBlock(docName, "txt", docB64),
-					llm.TextBlock(prompt),
-				),
-			},
-		})
+		docName := strings.TrimSuffix(filepath.Base(inputFile.RelPath), filepath.Ext(inputFile.RelPath))
+
+		res, err := summarizeFile(ctx, sz, inputFile.AbsPath, ext, mime, docName, cfg, pc)
 		if err != nil {
-			if ge, ok := llm.IsGovernorError(err); ok {
-				switch {
-				case ge.IsBudgetExceeded():
-					log.Fatalf("LLM budget exceeded: %s", ge.Msg)
-				case ge.IsModelNotAllowed():
-					log.Fatalf("Model not allowed. Available models: %v", ge.AllowedModels)
-				default:
-					log.Fatalf("Governor error [%s]: %s", ge.Code, ge.Msg)
-				}
-			}
-			log.Fatalf("Failed to invoke LLM: %v", err)
+			log.Printf("Failed to summarize %s: %v", inputFile.RelPath, err)
+			reports = append(reports, fileReport{File: inputFile.RelPath, Status: "failed", Error: err.Error(), Attempts: res.Attempts, CostUsd: res.CostUsd})
+			failed++
+			continue
 		}
 
-		summary := resp.Text()
-		log.Printf("Received summary (%d chars, cost: $%.4f)", len(summary), resp.Usage.EstimatedCostUsd)
-
-		// Generate PDF
-		baseName := strings.TrimSuffix(filepath.Base(jsonFile), filepath.Ext(jsonFile))
-		pdfPath := filepath.Join(outputDir, baseName+"-summary.pdf")
+		relDir := filepath.Dir(inputFile.RelPath)
+		pdfDir := filepath.Join(outputDir, relDir)
+		if err := os.MkdirAll(pdfDir, 0o755); err != nil {
+			log.Printf("Failed to create output directory for %s: %v", inputFile.RelPath, err)
+			reports = append(reports, fileReport{File: inputFile.RelPath, Status: "failed", Error: err.Error(), Attempts: res.Attempts, CostUsd: res.CostUsd})
+			failed++
+			continue
+		}
+		pdfPath := filepath.Join(pdfDir, docName+"-summary.pdf")
 
-		if err := generatePDF(pdfPath, baseName, summary); err != nil {
-			log.Fatalf("Failed to generate PDF: %v", err)
+		if err := generatePDF(pdfPath, docName, res.Text); err != nil {
+			log.Printf("Failed to generate PDF for %s: %v", inputFile.RelPath, err)
+			reports = append(reports, fileReport{File: inputFile.RelPath, Status: "failed", Error: err.Error(), Attempts: res.Attempts, CostUsd: res.CostUsd})
+			failed++
+			continue
 		}
 
 		log.Printf("Written: %s", pdfPath)
+		reports = append(reports, fileReport{File: inputFile.RelPath, Status: "success", Attempts: res.Attempts, CostUsd: res.CostUsd})
+	}
+
+	if err := writeRunSummary(outputDir, executionRunID, reports); err != nil {
+		log.Fatalf("Failed to write run summary: %v", err)
+	}
+
+	if failed > 0 {
+		log.Fatalf("%d of %d file(s) failed; see run-summary.json in %s", failed, len(inputFiles), outputDir)
 	}
 
 	log.Println("LLM Summarizer Processor complete")
@@ -176,4 +189,4 @@ func generatePDF(path, title, body string) error {
 	pdf.MultiCell(0, 6, body, "", "L", false)
 
 	return pdf.OutputFileAndClose(path)
-}
\ No newline at end of file
+}
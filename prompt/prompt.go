@@ -0,0 +1,90 @@
+// Package prompt resolves and renders the text/template files that shape
+// the system and user prompt sent to a Summarizer, so the processor's
+// vocabulary can be customized per deployment without a rebuild.
+package prompt
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// builtinFS embeds the repo's built-in templates and their shared
+// fragments, so resolving one doesn't depend on the process's working
+// directory matching the repo layout at runtime.
+//
+//go:embed templates
+var builtinFS embed.FS
+
+// builtinDir is the root of builtinFS, and the prefix a built-in template
+// name is resolved under (e.g. "dataset-summary" -> templates/dataset-summary.tmpl).
+const builtinDir = "templates"
+
+// Vars holds the dataset-derived values available to a prompt template.
+type Vars struct {
+	DocName        string
+	Ext            string
+	SizeBytes      int
+	SchemaJSON     string
+	SampleRows     string
+	ExecutionRunID string
+}
+
+// Resolve loads the prompt template named by ref, which may be a built-in
+// template name (e.g. "dataset-summary", "schema-only") or a path to a
+// text/template file on disk. An empty ref returns a nil *template.Template
+// and a nil error, signaling the caller should fall back to its hardcoded
+// prompt.
+func Resolve(ref string) (*template.Template, error) {
+	if ref == "" {
+		return nil, nil
+	}
+
+	if _, err := os.Stat(ref); err == nil {
+		tmpl, err := template.New(filepath.Base(ref)).Funcs(template.FuncMap{"readFile": readFile}).ParseFiles(ref)
+		if err != nil {
+			return nil, fmt.Errorf("prompt: load template %q: %w", ref, err)
+		}
+		return tmpl, nil
+	}
+
+	name := ref + ".tmpl"
+	data, err := builtinFS.ReadFile(filepath.Join(builtinDir, name))
+	if err != nil {
+		return nil, fmt.Errorf("prompt: unknown built-in template %q: %w", ref, err)
+	}
+	tmpl, err := template.New(name).Funcs(template.FuncMap{"readFile": readFile}).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("prompt: load template %q: %w", ref, err)
+	}
+	return tmpl, nil
+}
+
+// Render executes tmpl with vars and returns the resulting prompt text.
+func Render(tmpl *template.Template, vars Vars) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("prompt: render template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// readFile is exposed to templates as {{ readFile "path" }} so a shared
+// fragment (a disclaimer, a house style note, ...) can be included from
+// more than one template without duplicating it. It checks builtinFS first
+// so a built-in template's own fragments (e.g. "templates/fragments/footer.txt")
+// resolve regardless of working directory, then falls back to disk so a
+// user-supplied template can still pull in a fragment of its own.
+func readFile(path string) (string, error) {
+	if data, err := builtinFS.ReadFile(path); err == nil {
+		return string(data), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("prompt: readFile %q: %w", path, err)
+	}
+	return string(data), nil
+}
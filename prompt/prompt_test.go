@@ -0,0 +1,154 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveEmptyRefFallsBack(t *testing.T) {
+	tmpl, err := Resolve("")
+	if err != nil {
+		t.Fatalf("Resolve(\"\"): %v", err)
+	}
+	if tmpl != nil {
+		t.Fatalf("Resolve(\"\") = %v, want nil so the caller falls back to its hardcoded prompt", tmpl)
+	}
+}
+
+func TestResolveExplicitPath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "custom.tmpl")
+	if err := os.WriteFile(path, []byte("Summarize {{ .DocName }}."), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+
+	tmpl, err := Resolve(path)
+	if err != nil {
+		t.Fatalf("Resolve(%q): %v", path, err)
+	}
+
+	got, err := Render(tmpl, Vars{DocName: "dataset.json"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if want := "Summarize dataset.json."; got != want {
+		t.Fatalf("Render = %q, want %q", got, want)
+	}
+}
+
+func TestResolveUnknownRefErrors(t *testing.T) {
+	if _, err := Resolve("does-not-exist-anywhere"); err == nil {
+		t.Fatal("expected an error for a ref that is neither a file nor a known built-in")
+	}
+}
+
+// TestResolveBuiltinTemplatesEndToEnd resolves and renders every shipped
+// built-in template via its name, not a file path, so it exercises the
+// embedded-filesystem lookup (including the built-in templates' own
+// {{ readFile "templates/fragments/..." }} calls) the way runProcessor
+// actually calls Resolve. It must pass regardless of the test binary's
+// working directory.
+func TestResolveBuiltinTemplatesEndToEnd(t *testing.T) {
+	for _, name := range []string{"dataset-summary", "schema-only"} {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := Resolve(name)
+			if err != nil {
+				t.Fatalf("Resolve(%q): %v", name, err)
+			}
+
+			got, err := Render(tmpl, Vars{
+				DocName:        "dataset.csv",
+				Ext:            "csv",
+				SizeBytes:      123,
+				SchemaJSON:     `{"id":"int"}`,
+				SampleRows:     "id\n1",
+				ExecutionRunID: "run-1",
+			})
+			if err != nil {
+				t.Fatalf("Render(%q): %v", name, err)
+			}
+
+			for _, want := range []string{"dataset.csv", "Pennsieve dataset curators"} {
+				if !strings.Contains(got, want) {
+					t.Errorf("Resolve(%q) rendered %q, want it to contain %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderAllVars(t *testing.T) {
+	tmplText := `{{ .DocName }}|{{ .Ext }}|{{ .SizeBytes }}|{{ .SchemaJSON }}|{{ .SampleRows }}|{{ .ExecutionRunID }}`
+	dir := t.TempDir()
+	path := filepath.Join(dir, "vars.tmpl")
+	if err := os.WriteFile(path, []byte(tmplText), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+
+	tmpl, err := Resolve(path)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+
+	got, err := Render(tmpl, Vars{
+		DocName:        "d.csv",
+		Ext:            "csv",
+		SizeBytes:      42,
+		SchemaJSON:     `{"a":"int"}`,
+		SampleRows:     "a\n1",
+		ExecutionRunID: "run-1",
+	})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	want := `d.csv|csv|42|{"a":"int"}|a` + "\n" + `1|run-1`
+	if got != want {
+		t.Fatalf("Render = %q, want %q", got, want)
+	}
+}
+
+func TestRenderReadFileFunction(t *testing.T) {
+	dir := t.TempDir()
+	fragment := filepath.Join(dir, "footer.txt")
+	if err := os.WriteFile(fragment, []byte("shared footer"), 0o644); err != nil {
+		t.Fatalf("write fragment: %v", err)
+	}
+
+	tmplPath := filepath.Join(dir, "main.tmpl")
+	tmplText := `body {{ readFile "` + filepath.ToSlash(fragment) + `" }}`
+	if err := os.WriteFile(tmplPath, []byte(tmplText), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+
+	tmpl, err := Resolve(tmplPath)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+
+	got, err := Render(tmpl, Vars{})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if !strings.Contains(got, "shared footer") {
+		t.Fatalf("Render = %q, want it to include the fragment read via readFile", got)
+	}
+}
+
+func TestRenderReadFileMissingFragmentErrors(t *testing.T) {
+	dir := t.TempDir()
+	tmplPath := filepath.Join(dir, "main.tmpl")
+	if err := os.WriteFile(tmplPath, []byte(`{{ readFile "nope.txt" }}`), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+
+	tmpl, err := Resolve(tmplPath)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+
+	if _, err := Render(tmpl, Vars{}); err == nil {
+		t.Fatal("expected an error when readFile's path does not exist")
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// fileReport records one input file's outcome for run-summary.json.
+type fileReport struct {
+	File     string  `json:"file"`
+	Status   string  `json:"status"`
+	Error    string  `json:"error,omitempty"`
+	Attempts int     `json:"attempts,omitempty"`
+	CostUsd  float64 `json:"costUsd,omitempty"`
+}
+
+// runSummary is the aggregate report written to outputDir/run-summary.json
+// after every input file has been processed, so a per-file failure doesn't
+// cost visibility into the files that did succeed.
+type runSummary struct {
+	ExecutionRunID string       `json:"executionRunId"`
+	Total          int          `json:"total"`
+	Succeeded      int          `json:"succeeded"`
+	Failed         int          `json:"failed"`
+	TotalCostUsd   float64      `json:"totalCostUsd"`
+	Files          []fileReport `json:"files"`
+}
+
+// writeRunSummary builds and writes run-summary.json from reports.
+func writeRunSummary(outputDir, executionRunID string, reports []fileReport) error {
+	summary := runSummary{
+		ExecutionRunID: executionRunID,
+		Total:          len(reports),
+		Files:          reports,
+	}
+	for _, r := range reports {
+		summary.TotalCostUsd += r.CostUsd
+		if r.Status == "success" {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+	}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode run summary: %w", err)
+	}
+
+	path := filepath.Join(outputDir, "run-summary.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
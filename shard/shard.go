@@ -0,0 +1,136 @@
+// Package shard splits an oversized input document into smaller pieces so
+// that runProcessor can summarize each piece with its own Summarize call
+// (the "map" phase) before asking the LLM to combine the partial summaries
+// into one narrative (the "reduce" phase).
+package shard
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pennsieve/processor-test-llm-summarizer/formats"
+)
+
+// DefaultShardSize is the number of elements — array items or top-level
+// object keys — carried in each shard when an oversized JSON-shaped input
+// must be split for map-reduce summarization.
+const DefaultShardSize = 1000
+
+// Shard is one slice of an oversized input, encoded as canonical JSON and
+// ready to be summarized independently in the map phase.
+type Shard struct {
+	Index int
+	Total int
+	Data  []byte
+}
+
+// Split divides parsed — a value produced by formats.Decode — into shards
+// of at most shardSize elements. JSON arrays are split by element count,
+// objects are split by top-level key, and anything else (a scalar, or a
+// shape that has no natural subdivision) comes back as a single shard.
+func Split(parsed any, shardSize int) ([]Shard, error) {
+	if shardSize <= 0 {
+		shardSize = DefaultShardSize
+	}
+
+	switch v := parsed.(type) {
+	case []interface{}:
+		return splitArray(v, shardSize)
+	case map[string]interface{}:
+		return splitObject(v, shardSize)
+	default:
+		data, err := json.Marshal(parsed)
+		if err != nil {
+			return nil, fmt.Errorf("shard: encode single shard: %w", err)
+		}
+		return []Shard{{Index: 0, Total: 1, Data: data}}, nil
+	}
+}
+
+func splitArray(items []interface{}, shardSize int) ([]Shard, error) {
+	total := (len(items) + shardSize - 1) / shardSize
+	if total == 0 {
+		total = 1
+	}
+
+	shards := make([]Shard, 0, total)
+	for i := 0; i < len(items); i += shardSize {
+		end := i + shardSize
+		if end > len(items) {
+			end = len(items)
+		}
+		data, err := json.Marshal(items[i:end])
+		if err != nil {
+			return nil, fmt.Errorf("shard: encode array shard %d: %w", len(shards), err)
+		}
+		shards = append(shards, Shard{Index: len(shards), Total: total, Data: data})
+	}
+	if len(shards) == 0 {
+		data, err := json.Marshal(items)
+		if err != nil {
+			return nil, fmt.Errorf("shard: encode empty array: %w", err)
+		}
+		shards = append(shards, Shard{Index: 0, Total: 1, Data: data})
+	}
+	return shards, nil
+}
+
+func splitObject(obj map[string]interface{}, shardSize int) ([]Shard, error) {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+
+	total := (len(keys) + shardSize - 1) / shardSize
+	if total == 0 {
+		total = 1
+	}
+
+	shards := make([]Shard, 0, total)
+	for i := 0; i < len(keys); i += shardSize {
+		end := i + shardSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		part := make(map[string]interface{}, end-i)
+		for _, k := range keys[i:end] {
+			part[k] = obj[k]
+		}
+		data, err := json.Marshal(part)
+		if err != nil {
+			return nil, fmt.Errorf("shard: encode object shard %d: %w", len(shards), err)
+		}
+		shards = append(shards, Shard{Index: len(shards), Total: total, Data: data})
+	}
+	if len(shards) == 0 {
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("shard: encode empty object: %w", err)
+		}
+		shards = append(shards, Shard{Index: 0, Total: 1, Data: data})
+	}
+	return shards, nil
+}
+
+// SplitCSV divides a CSV's data rows into row-range shards, carrying the
+// header into every shard and summarizing each shard's rows on its own so
+// the map phase sees an accurate per-shard schema.
+func SplitCSV(header []string, rows [][]string, shardSize int) []formats.CSVSchema {
+	if shardSize <= 0 {
+		shardSize = DefaultShardSize
+	}
+
+	if len(rows) == 0 {
+		return []formats.CSVSchema{formats.SummarizeCSV(header, rows)}
+	}
+
+	var shards []formats.CSVSchema
+	for i := 0; i < len(rows); i += shardSize {
+		end := i + shardSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		shards = append(shards, formats.SummarizeCSV(header, rows[i:end]))
+	}
+	return shards
+}
@@ -0,0 +1,116 @@
+package shard
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSplitArrayExactBoundaries(t *testing.T) {
+	items := make([]interface{}, 5)
+	for i := range items {
+		items[i] = i
+	}
+
+	shards, err := Split(items, 2)
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	if len(shards) != 3 {
+		t.Fatalf("len(shards) = %d, want 3", len(shards))
+	}
+
+	var got [][]int
+	for _, s := range shards {
+		var part []int
+		if err := json.Unmarshal(s.Data, &part); err != nil {
+			t.Fatalf("unmarshal shard %d: %v", s.Index, err)
+		}
+		got = append(got, part)
+		if s.Total != 3 {
+			t.Errorf("shard %d.Total = %d, want 3", s.Index, s.Total)
+		}
+	}
+
+	want := [][]int{{0, 1}, {2, 3}, {4}}
+	if !equalIntSlices(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestSplitObjectExactBoundaries(t *testing.T) {
+	obj := map[string]interface{}{"a": 1, "b": 2, "c": 3}
+
+	shards, err := Split(obj, 2)
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	if len(shards) != 2 {
+		t.Fatalf("len(shards) = %d, want 2", len(shards))
+	}
+
+	total := 0
+	for _, s := range shards {
+		var part map[string]interface{}
+		if err := json.Unmarshal(s.Data, &part); err != nil {
+			t.Fatalf("unmarshal shard %d: %v", s.Index, err)
+		}
+		total += len(part)
+	}
+	if total != len(obj) {
+		t.Fatalf("total keys across shards = %d, want %d", total, len(obj))
+	}
+}
+
+func TestSplitScalarIsSingleShard(t *testing.T) {
+	shards, err := Split("just a string", 2)
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	if len(shards) != 1 || shards[0].Total != 1 {
+		t.Fatalf("got %+v, want a single shard", shards)
+	}
+}
+
+func TestSplitCSVRowRangeBoundaries(t *testing.T) {
+	header := []string{"id"}
+	rows := [][]string{{"1"}, {"2"}, {"3"}, {"4"}, {"5"}}
+
+	schemas := SplitCSV(header, rows, 2)
+	if len(schemas) != 3 {
+		t.Fatalf("len(schemas) = %d, want 3", len(schemas))
+	}
+
+	wantCounts := []int{2, 2, 1}
+	for i, s := range schemas {
+		if s.RowCount != wantCounts[i] {
+			t.Errorf("schemas[%d].RowCount = %d, want %d", i, s.RowCount, wantCounts[i])
+		}
+		if len(s.Columns) != 1 || s.Columns[0].Name != "id" {
+			t.Errorf("schemas[%d].Columns = %+v, header not carried into shard", i, s.Columns)
+		}
+	}
+}
+
+func TestSplitCSVEmptyRows(t *testing.T) {
+	schemas := SplitCSV([]string{"id"}, nil, 2)
+	if len(schemas) != 1 || schemas[0].RowCount != 0 {
+		t.Fatalf("got %+v, want a single empty-row schema", schemas)
+	}
+}
+
+func equalIntSlices(a, b [][]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/pennsieve/processor-test-llm-summarizer/formats"
+	"github.com/pennsieve/processor-test-llm-summarizer/prompt"
+	"github.com/pennsieve/processor-test-llm-summarizer/shard"
+	"github.com/pennsieve/processor-test-llm-summarizer/summarizer"
+)
+
+// systemPrompt is sent to every backend alongside each document.
+const systemPrompt = "You are a data analyst. Summarize datasets clearly and concisely. Use plain text paragraphs, not markdown."
+
+// promptConfig carries the resolved user prompt template (nil when none is
+// configured) and the values it needs that aren't derived per-file.
+type promptConfig struct {
+	Template       *template.Template
+	ExecutionRunID string
+}
+
+// summarizeFile decodes inputFile and returns its LLM-generated narrative
+// summary, transparently map-reduce summarizing across shards when the
+// document is too large for a single Summarize call.
+func summarizeFile(ctx context.Context, sz summarizer.Summarizer, inputFile, ext string, mime formats.MimeType, docName string, cfg shardConfig, pc promptConfig) (summarizer.Result, error) {
+	if mime == formats.MimeCSV {
+		return summarizeCSVFile(ctx, sz, inputFile, docName, cfg, pc)
+	}
+	return summarizeJSONLikeFile(ctx, sz, inputFile, ext, mime, docName, cfg, pc)
+}
+
+func summarizeJSONLikeFile(ctx context.Context, sz summarizer.Summarizer, inputFile, ext string, mime formats.MimeType, docName string, cfg shardConfig, pc promptConfig) (summarizer.Result, error) {
+	f, err := os.Open(inputFile)
+	if err != nil {
+		return summarizer.Result{}, fmt.Errorf("open %s: %w", inputFile, err)
+	}
+	defer f.Close()
+
+	parsed, err := formats.Decode(f, ext)
+	if err != nil {
+		return summarizer.Result{}, fmt.Errorf("decode %s: %w", inputFile, err)
+	}
+
+	canonical, err := json.Marshal(parsed)
+	if err != nil {
+		return summarizer.Result{}, fmt.Errorf("encode %s as JSON: %w", inputFile, err)
+	}
+
+	if base64.StdEncoding.EncodedLen(len(canonical)) <= cfg.ThresholdBytes {
+		promptText, err := buildPrompt(pc, mime, docName, ext, len(canonical), jsonSchemaSummary(parsed), jsonSampleRows(parsed))
+		if err != nil {
+			return summarizer.Result{}, fmt.Errorf("build prompt for %s: %w", inputFile, err)
+		}
+		return invokeDocument(ctx, sz, docName, mime, canonical, promptText)
+	}
+
+	log.Printf("%s exceeds the %d-byte shard threshold; summarizing in shards", filepath.Base(inputFile), cfg.ThresholdBytes)
+
+	shards, err := shard.Split(parsed, cfg.ShardSize)
+	if err != nil {
+		return summarizer.Result{}, fmt.Errorf("shard %s: %w", inputFile, err)
+	}
+
+	shardData := make([][]byte, len(shards))
+	for i, s := range shards {
+		shardData[i] = s.Data
+	}
+
+	partials, err := mapShards(ctx, sz, docName, mime, shardData, cfg.Concurrency)
+	if err != nil {
+		return partialResult(partials), err
+	}
+	return reduceSummaries(ctx, sz, mime, partials)
+}
+
+// summarizeCSVFile is handled separately from summarizeJSONLikeFile because
+// formats.Decode already reduces a CSV file to a compact CSVSchema (a
+// handful of sample rows), so the encoded-document-size check the other
+// formats use would never trip. Instead the sharding decision is based on
+// the raw file size, and each shard gets its own schema summarized from
+// just that shard's row range.
+func summarizeCSVFile(ctx context.Context, sz summarizer.Summarizer, inputFile, docName string, cfg shardConfig, pc promptConfig) (summarizer.Result, error) {
+	info, err := os.Stat(inputFile)
+	if err != nil {
+		return summarizer.Result{}, fmt.Errorf("stat %s: %w", inputFile, err)
+	}
+
+	f, err := os.Open(inputFile)
+	if err != nil {
+		return summarizer.Result{}, fmt.Errorf("open %s: %w", inputFile, err)
+	}
+	defer f.Close()
+
+	header, rows, err := formats.ReadCSV(f)
+	if err != nil {
+		return summarizer.Result{}, fmt.Errorf("decode %s: %w", inputFile, err)
+	}
+
+	if info.Size() <= int64(cfg.ThresholdBytes) {
+		schema := formats.SummarizeCSV(header, rows)
+		canonical, err := json.Marshal(schema)
+		if err != nil {
+			return summarizer.Result{}, fmt.Errorf("encode %s as JSON: %w", inputFile, err)
+		}
+		schemaJSON, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return summarizer.Result{}, fmt.Errorf("encode %s schema as JSON: %w", inputFile, err)
+		}
+		promptText, err := buildPrompt(pc, formats.MimeCSV, docName, "csv", int(info.Size()), string(schemaJSON), formatSampleRows(header, schema.SampleRows))
+		if err != nil {
+			return summarizer.Result{}, fmt.Errorf("build prompt for %s: %w", inputFile, err)
+		}
+		return invokeDocument(ctx, sz, docName, formats.MimeCSV, canonical, promptText)
+	}
+
+	log.Printf("%s exceeds the %d-byte shard threshold; summarizing in row-range shards", filepath.Base(inputFile), cfg.ThresholdBytes)
+
+	schemas := shard.SplitCSV(header, rows, cfg.ShardSize)
+	shardData := make([][]byte, len(schemas))
+	for i, s := range schemas {
+		data, err := json.Marshal(s)
+		if err != nil {
+			return summarizer.Result{}, fmt.Errorf("encode csv shard %d: %w", i, err)
+		}
+		shardData[i] = data
+	}
+
+	partials, err := mapShards(ctx, sz, docName, formats.MimeCSV, shardData, cfg.Concurrency)
+	if err != nil {
+		return partialResult(partials), err
+	}
+	return reduceSummaries(ctx, sz, formats.MimeCSV, partials)
+}
+
+// buildPrompt renders pc's configured template with the given document
+// metadata, falling back to the hardcoded datasetPrompt when no template is
+// configured. It is only used for the single-document (non-sharded) call;
+// the map and reduce phases keep their own hardcoded orchestration prompts
+// since those describe shard mechanics rather than the dataset itself.
+func buildPrompt(pc promptConfig, mime formats.MimeType, docName, ext string, sizeBytes int, schemaJSON, sampleRows string) (string, error) {
+	if pc.Template == nil {
+		return datasetPrompt(mime), nil
+	}
+	return prompt.Render(pc.Template, prompt.Vars{
+		DocName:        docName,
+		Ext:            ext,
+		SizeBytes:      sizeBytes,
+		SchemaJSON:     schemaJSON,
+		SampleRows:     sampleRows,
+		ExecutionRunID: pc.ExecutionRunID,
+	})
+}
+
+// jsonSchemaSummary renders a best-effort "schema" for a decoded JSON-like
+// document: the sorted field names and JSON type names found at the top
+// level of the first representative object, pretty-printed as JSON so it
+// reads naturally inside a prompt template.
+func jsonSchemaSummary(parsed any) string {
+	shape := jsonShape(parsed)
+	data, err := json.MarshalIndent(shape, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", shape)
+	}
+	return string(data)
+}
+
+// jsonShape describes v's structure: for an object it's a map of field name
+// to the JSON type name of its value; for a non-empty array it's the shape
+// of the first element wrapped to note it repeats; anything else is its
+// JSON type name.
+func jsonShape(v any) any {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		shape := make(map[string]any, len(keys))
+		for _, k := range keys {
+			shape[k] = jsonTypeName(t[k])
+		}
+		return shape
+	case []interface{}:
+		if len(t) == 0 {
+			return "array<empty>"
+		}
+		return map[string]any{"array of": jsonShape(t[0])}
+	default:
+		return jsonTypeName(v)
+	}
+}
+
+// jsonTypeName names v's type in the vocabulary a prompt reader expects
+// ("string", "number", "boolean", "object", "array", "null").
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, int, int64, uint64:
+		return "number"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		return "unknown"
+	}
+}
+
+// jsonSampleRows renders up to csvSampleSize-equivalent sample elements when
+// parsed is a top-level JSON array, so array-shaped documents get the same
+// "sample rows" prompt variable CSV documents do. It returns "" for
+// non-array documents.
+func jsonSampleRows(parsed any) string {
+	arr, ok := parsed.([]interface{})
+	if !ok || len(arr) == 0 {
+		return ""
+	}
+
+	sampleCount := 5
+	if sampleCount > len(arr) {
+		sampleCount = len(arr)
+	}
+
+	var b strings.Builder
+	for i, elem := range arr[:sampleCount] {
+		data, err := json.Marshal(elem)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "%d. %s\n", i+1, data)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formatSampleRows renders a CSVSchema's sample rows as a header line
+// followed by one comma-joined line per row, matching how a user would
+// expect to see a CSV excerpt inside a prompt.
+func formatSampleRows(header []string, rows [][]string) string {
+	if len(rows) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Join(header, ","))
+	b.WriteByte('\n')
+	for _, row := range rows {
+		b.WriteString(strings.Join(row, ","))
+		b.WriteByte('\n')
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// mapShards runs the per-shard "map" phase, calling Summarize once per
+// shard with bounded concurrency so a single large file doesn't serialize
+// the whole run.
+func mapShards(ctx context.Context, sz summarizer.Summarizer, docName string, mime formats.MimeType, shardData [][]byte, concurrency int) ([]summarizer.Result, error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	total := len(shardData)
+	results := make([]summarizer.Result, total)
+	errs := make([]error, total)
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, data := range shardData {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, data []byte) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			promptText := fmt.Sprintf(`This is shard %d of %d of a larger %s dataset that was split for summarization. Summarize only what this shard contains: key fields, notable values, and any patterns. A later step will combine every shard's summary into one narrative.`, i+1, total, mime)
+
+			res, err := invokeDocument(ctx, sz, fmt.Sprintf("%s-shard-%d", docName, i+1), mime, data, promptText)
+			results[i] = res
+			errs[i] = err
+		}(i, data)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			// Return every shard result gathered so far alongside the error,
+			// so the caller can still fold the cost and attempts of whatever
+			// shards completed (real, billed governor calls) into the
+			// file's aggregate cost instead of discarding them.
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// partialResult folds the cost and attempts of whatever shards a failed
+// mapShards call did manage to summarize into a single Result, so a
+// map-phase error still reports the real, billed cost incurred before it
+// failed instead of the zero value.
+func partialResult(partials []summarizer.Result) summarizer.Result {
+	var res summarizer.Result
+	for _, p := range partials {
+		res.CostUsd += p.CostUsd
+		res.Attempts += p.Attempts
+	}
+	return res
+}
+
+// reduceSummaries runs the "reduce" phase: it feeds every shard's partial
+// summary back to the LLM with an aggregation prompt to produce the final
+// narrative that gets written to PDF. The returned cost and attempts fold
+// in every map-phase call alongside the reduce call itself.
+func reduceSummaries(ctx context.Context, sz summarizer.Summarizer, mime formats.MimeType, partials []summarizer.Result) (summarizer.Result, error) {
+	var b strings.Builder
+	var mapCost float64
+	var mapAttempts int
+	for i, p := range partials {
+		mapCost += p.CostUsd
+		mapAttempts += p.Attempts
+		fmt.Fprintf(&b, "Shard %d summary:\n%s\n\n", i+1, p.Text)
+	}
+
+	promptText := fmt.Sprintf(`The text below contains independent summaries of %d shards of a single %s dataset that was too large to summarize in one pass. Combine them into one comprehensive summary that includes:
+
+1. Overview: What this dataset contains and its purpose
+2. Structure: The key fields and their types
+3. Content Summary: A description of the data values and any patterns across all shards
+4. Potential Uses: What this dataset could be used for
+
+Shard summaries:
+%s`, len(partials), mime, b.String())
+
+	res, err := sz.Summarize(ctx, summarizer.Document{
+		Name:      "reduce",
+		Format:    string(mime),
+		Canonical: []byte(b.String()),
+		Prompt:    promptText,
+		System:    systemPrompt,
+	})
+	res.CostUsd += mapCost
+	res.Attempts += mapAttempts
+	if err != nil {
+		return res, fmt.Errorf("reduce shard summaries: %w", err)
+	}
+
+	log.Printf("Received reduced summary (%d chars, cost: $%.4f)", len(res.Text), res.CostUsd)
+	return res, nil
+}
+
+// invokeDocument calls sz.Summarize with canonical (the document's
+// canonical JSON representation) and prompt and returns the resulting
+// narrative text.
+func invokeDocument(ctx context.Context, sz summarizer.Summarizer, docName string, mime formats.MimeType, canonical []byte, promptText string) (summarizer.Result, error) {
+	res, err := sz.Summarize(ctx, summarizer.Document{
+		Name:      docName,
+		Format:    string(mime),
+		Canonical: canonical,
+		Prompt:    promptText,
+		System:    systemPrompt,
+	})
+	if err != nil {
+		return res, fmt.Errorf("summarize %s: %w", docName, err)
+	}
+
+	if res.Attempts > 1 {
+		log.Printf("Invoked summarizer for %s after %d attempt(s)", docName, res.Attempts)
+	}
+	log.Printf("Received summary for %s (%d chars, cost: $%.4f)", docName, len(res.Text), res.CostUsd)
+	return res, nil
+}
+
+// datasetPrompt builds the single-call dataset summary prompt, noting the
+// document's original source format so the LLM knows the source shape.
+func datasetPrompt(mime formats.MimeType) string {
+	return fmt.Sprintf(`The attached document was sourced from a %s file and is provided below as its canonical JSON representation. It represents a dataset. Please provide a comprehensive summary that includes:
+
+1. Overview: What this dataset contains and its purpose
+2. Structure: The key fields and their types
+3. Content Summary: A description of the data values and any patterns
+4. Potential Uses: What this dataset could be used for`, mime)
+}
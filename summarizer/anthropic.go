@@ -0,0 +1,110 @@
+package summarizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+const defaultAnthropicModel = "claude-3-5-haiku-latest"
+
+// AnthropicSummarizer calls the Anthropic Messages API directly, bypassing
+// the Pennsieve governor. It exists so contributors can develop and test
+// the sharding/retry/PDF paths against a real model without Bedrock
+// access.
+type AnthropicSummarizer struct {
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+// NewAnthropicSummarizer constructs an AnthropicSummarizer, reading its
+// API key from ANTHROPIC_API_KEY. An empty model falls back to
+// defaultAnthropicModel.
+func NewAnthropicSummarizer(model string) (*AnthropicSummarizer, error) {
+	apiKey := os.Getenv("ANTHROPIC_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("summarizer: ANTHROPIC_API_KEY is required for the anthropic backend")
+	}
+	if model == "" {
+		model = defaultAnthropicModel
+	}
+	return &AnthropicSummarizer{apiKey: apiKey, model: model, client: &http.Client{Timeout: 60 * time.Second}}, nil
+}
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	MaxTokens int                `json:"max_tokens"`
+	System    string             `json:"system,omitempty"`
+	Messages  []anthropicMessage `json:"messages"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+func (s *AnthropicSummarizer) Summarize(ctx context.Context, doc Document) (Result, error) {
+	reqBody, err := json.Marshal(anthropicRequest{
+		Model:     s.model,
+		MaxTokens: 2048,
+		System:    doc.System,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: doc.Prompt + "\n\n" + string(doc.Canonical)},
+		},
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("summarizer: encode anthropic request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.anthropic.com/v1/messages", bytes.NewReader(reqBody))
+	if err != nil {
+		return Result{}, fmt.Errorf("summarizer: build anthropic request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", s.apiKey)
+	httpReq.Header.Set("anthropic-version", "2023-06-01")
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return Result{Attempts: 1}, fmt.Errorf("summarizer: anthropic request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Result{Attempts: 1}, fmt.Errorf("summarizer: read anthropic response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Result{Attempts: 1}, fmt.Errorf("summarizer: anthropic request failed (%d): %s", resp.StatusCode, body)
+	}
+
+	var parsed anthropicResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return Result{Attempts: 1}, fmt.Errorf("summarizer: decode anthropic response: %w", err)
+	}
+	if len(parsed.Content) == 0 {
+		return Result{Attempts: 1}, fmt.Errorf("summarizer: anthropic response had no content")
+	}
+
+	return Result{
+		Text:     parsed.Content[0].Text,
+		CostUsd:  estimateCost(anthropicRates, s.model, parsed.Usage.InputTokens, parsed.Usage.OutputTokens),
+		Attempts: 1,
+	}, nil
+}
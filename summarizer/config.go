@@ -0,0 +1,50 @@
+package summarizer
+
+import (
+	"fmt"
+
+	"github.com/pennsieve/processor-test-llm-summarizer/internal/retry"
+)
+
+// Backend names a Summarizer implementation, selected via the
+// SUMMARIZER_BACKEND env var.
+type Backend string
+
+const (
+	// BackendGovernor routes through the Pennsieve LLM governor and
+	// Bedrock. It is the default when Backend is empty.
+	BackendGovernor Backend = "governor"
+	// BackendOpenAI calls the OpenAI chat completions API directly.
+	BackendOpenAI Backend = "openai"
+	// BackendAnthropic calls the Anthropic Messages API directly.
+	BackendAnthropic Backend = "anthropic"
+	// BackendMock produces a deterministic, schema-derived summary with no
+	// network calls, for CI and offline development.
+	BackendMock Backend = "mock"
+)
+
+// Config selects and configures a Summarizer.
+type Config struct {
+	Backend Backend
+	Model   string
+	// Retry governs the governor backend's retry/backoff behavior. It is
+	// ignored by the other backends, which make a single attempt per call.
+	Retry retry.Config
+}
+
+// New constructs the Summarizer selected by cfg.Backend, defaulting to the
+// Pennsieve governor when Backend is empty.
+func New(cfg Config) (Summarizer, error) {
+	switch cfg.Backend {
+	case "", BackendGovernor:
+		return NewGovernorSummarizer(cfg.Model, cfg.Retry), nil
+	case BackendOpenAI:
+		return NewOpenAISummarizer(cfg.Model)
+	case BackendAnthropic:
+		return NewAnthropicSummarizer(cfg.Model)
+	case BackendMock:
+		return NewMockSummarizer(), nil
+	default:
+		return nil, fmt.Errorf("summarizer: unknown backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,29 @@
+package summarizer
+
+// tokenRate holds rough published per-million-token pricing, used only to
+// populate Result.CostUsd for the direct-provider backends; the governor
+// backend gets its cost directly from Bedrock via llm.Response.Usage.
+type tokenRate struct {
+	InputPerMillion  float64
+	OutputPerMillion float64
+}
+
+var openAIRates = map[string]tokenRate{
+	"gpt-4o-mini": {InputPerMillion: 0.15, OutputPerMillion: 0.60},
+	"gpt-4o":      {InputPerMillion: 2.50, OutputPerMillion: 10.00},
+}
+
+var anthropicRates = map[string]tokenRate{
+	"claude-3-5-haiku-latest":  {InputPerMillion: 0.80, OutputPerMillion: 4.00},
+	"claude-3-5-sonnet-latest": {InputPerMillion: 3.00, OutputPerMillion: 15.00},
+}
+
+// estimateCost looks up model in rates and prices inputTokens/outputTokens
+// against it, returning 0 for an unrecognized model rather than failing.
+func estimateCost(rates map[string]tokenRate, model string, inputTokens, outputTokens int) float64 {
+	rate, ok := rates[model]
+	if !ok {
+		return 0
+	}
+	return float64(inputTokens)/1_000_000*rate.InputPerMillion + float64(outputTokens)/1_000_000*rate.OutputPerMillion
+}
@@ -0,0 +1,86 @@
+package summarizer
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+
+	"github.com/pennsieve/pennsieve-go-llm/llm"
+	"github.com/pennsieve/processor-test-llm-summarizer/internal/retry"
+)
+
+// GovernorSummarizer is the default Summarizer: it sends documents to
+// Bedrock through the Pennsieve LLM governor, retrying transient errors
+// with backoff.
+type GovernorSummarizer struct {
+	gov   *llm.Governor
+	model string
+	retry retry.Config
+}
+
+// NewGovernorSummarizer constructs a GovernorSummarizer. An empty model
+// falls back to llm.ModelHaiku45, the model the processor has always used.
+func NewGovernorSummarizer(model string, rc retry.Config) *GovernorSummarizer {
+	if model == "" {
+		model = llm.ModelHaiku45
+	}
+	return &GovernorSummarizer{gov: llm.NewGovernor(), model: model, retry: rc}
+}
+
+// Available reports whether the governor has been configured
+// (LLM_GOVERNOR_FUNCTION is set).
+func (s *GovernorSummarizer) Available() bool {
+	return s.gov.Available()
+}
+
+func (s *GovernorSummarizer) Summarize(ctx context.Context, doc Document) (Result, error) {
+	docB64 := base64.StdEncoding.EncodeToString(doc.Canonical)
+
+	req := &llm.InvokeRequest{
+		Model:     s.model,
+		System:    doc.System,
+		MaxTokens: 2048,
+		Messages: []llm.Message{
+			llm.UserMessage(
+				llm.DocumentBlock(doc.Name, "txt", docB64),
+				llm.TextBlock(doc.Prompt),
+			),
+		},
+	}
+
+	var resp *llm.Response
+	result, err := retry.Do(ctx, s.retry, isFatalGovernorErr, func(attemptCtx context.Context) error {
+		var invokeErr error
+		resp, invokeErr = s.gov.Invoke(attemptCtx, req)
+		return invokeErr
+	})
+	if err != nil {
+		return Result{Attempts: result.Attempts}, handleGovernorError(err)
+	}
+	return Result{Text: resp.Text(), CostUsd: resp.Usage.EstimatedCostUsd, Attempts: result.Attempts}, nil
+}
+
+// isFatalGovernorErr reports the governor errors that retrying can never
+// fix: an exceeded budget or a model the governor won't allow.
+func isFatalGovernorErr(err error) bool {
+	ge, ok := llm.IsGovernorError(err)
+	return ok && (ge.IsBudgetExceeded() || ge.IsModelNotAllowed())
+}
+
+// handleGovernorError classifies a governor error: budget-exceeded and
+// model-not-allowed remain fatal for the whole run, everything else is
+// returned so the caller can record a single file's failure and continue.
+func handleGovernorError(err error) error {
+	if ge, ok := llm.IsGovernorError(err); ok {
+		switch {
+		case ge.IsBudgetExceeded():
+			log.Fatalf("LLM budget exceeded: %s", ge.Msg)
+		case ge.IsModelNotAllowed():
+			log.Fatalf("Model not allowed. Available models: %v", ge.AllowedModels)
+		default:
+			return fmt.Errorf("governor error [%s]: %s", ge.Code, ge.Msg)
+		}
+	}
+	return fmt.Errorf("failed to invoke LLM: %w", err)
+}
@@ -0,0 +1,45 @@
+package summarizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MockSummarizer produces a deterministic, schema-derived summary without
+// calling any LLM, for local development and CI where Bedrock access and
+// cost aren't available.
+type MockSummarizer struct{}
+
+// NewMockSummarizer constructs a MockSummarizer.
+func NewMockSummarizer() *MockSummarizer {
+	return &MockSummarizer{}
+}
+
+func (s *MockSummarizer) Summarize(ctx context.Context, doc Document) (Result, error) {
+	var parsed interface{}
+	if err := json.Unmarshal(doc.Canonical, &parsed); err != nil {
+		// The reduce phase's canonical payload is the concatenated shard
+		// summaries, not JSON — describe it by size instead.
+		return Result{Text: fmt.Sprintf("Mock summary of %s: combined %d byte(s) of shard summaries.", doc.Name, len(doc.Canonical))}, nil
+	}
+	return Result{Text: describeSchema(doc.Name, parsed)}, nil
+}
+
+func describeSchema(name string, v interface{}) string {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return fmt.Sprintf("Mock summary of %s: an object with %d field(s): %s.", name, len(keys), strings.Join(keys, ", "))
+	case []interface{}:
+		return fmt.Sprintf("Mock summary of %s: an array of %d element(s).", name, len(t))
+	default:
+		return fmt.Sprintf("Mock summary of %s: a scalar value of type %T.", name, t)
+	}
+}
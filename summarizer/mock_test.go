@@ -0,0 +1,74 @@
+package summarizer
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMockSummarizerDescribesObjectSchema(t *testing.T) {
+	s := NewMockSummarizer()
+
+	res, err := s.Summarize(context.Background(), Document{
+		Name:      "dataset.json",
+		Canonical: []byte(`{"id":1,"name":"x"}`),
+	})
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	for _, want := range []string{"dataset.json", "id", "name", "2 field(s)"} {
+		if !strings.Contains(res.Text, want) {
+			t.Errorf("Text = %q, want it to contain %q", res.Text, want)
+		}
+	}
+}
+
+func TestMockSummarizerDescribesArraySchema(t *testing.T) {
+	s := NewMockSummarizer()
+
+	res, err := s.Summarize(context.Background(), Document{
+		Name:      "rows.json",
+		Canonical: []byte(`[1,2,3]`),
+	})
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if !strings.Contains(res.Text, "3 element(s)") {
+		t.Errorf("Text = %q, want it to mention the element count", res.Text)
+	}
+}
+
+func TestMockSummarizerHandlesNonJSONReduceInput(t *testing.T) {
+	s := NewMockSummarizer()
+
+	res, err := s.Summarize(context.Background(), Document{
+		Name:      "reduce",
+		Canonical: []byte("shard 1 summary\nshard 2 summary"),
+	})
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if !strings.Contains(res.Text, "reduce") {
+		t.Errorf("Text = %q, want it to mention the document name", res.Text)
+	}
+}
+
+func TestMockSummarizerIsDeterministic(t *testing.T) {
+	s := NewMockSummarizer()
+	doc := Document{Name: "dataset.json", Canonical: []byte(`{"a":1}`)}
+
+	first, err := s.Summarize(context.Background(), doc)
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	second, err := s.Summarize(context.Background(), doc)
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if first.Text != second.Text {
+		t.Fatalf("mock summary not deterministic: %q != %q", first.Text, second.Text)
+	}
+	if first.CostUsd != 0 {
+		t.Errorf("CostUsd = %v, want 0 for the mock backend", first.CostUsd)
+	}
+}
@@ -0,0 +1,105 @@
+package summarizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+const defaultOpenAIModel = "gpt-4o-mini"
+
+// OpenAISummarizer calls the OpenAI chat completions API directly,
+// bypassing the Pennsieve governor. It exists so contributors can develop
+// and test the sharding/retry/PDF paths against a real model without
+// Bedrock access.
+type OpenAISummarizer struct {
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+// NewOpenAISummarizer constructs an OpenAISummarizer, reading its API key
+// from OPENAI_API_KEY. An empty model falls back to defaultOpenAIModel.
+func NewOpenAISummarizer(model string) (*OpenAISummarizer, error) {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("summarizer: OPENAI_API_KEY is required for the openai backend")
+	}
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+	return &OpenAISummarizer{apiKey: apiKey, model: model, client: &http.Client{Timeout: 60 * time.Second}}, nil
+}
+
+type openAIRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+func (s *OpenAISummarizer) Summarize(ctx context.Context, doc Document) (Result, error) {
+	reqBody, err := json.Marshal(openAIRequest{
+		Model: s.model,
+		Messages: []openAIMessage{
+			{Role: "system", Content: doc.System},
+			{Role: "user", Content: doc.Prompt + "\n\n" + string(doc.Canonical)},
+		},
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("summarizer: encode openai request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.openai.com/v1/chat/completions", bytes.NewReader(reqBody))
+	if err != nil {
+		return Result{}, fmt.Errorf("summarizer: build openai request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+s.apiKey)
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return Result{Attempts: 1}, fmt.Errorf("summarizer: openai request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Result{Attempts: 1}, fmt.Errorf("summarizer: read openai response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Result{Attempts: 1}, fmt.Errorf("summarizer: openai request failed (%d): %s", resp.StatusCode, body)
+	}
+
+	var parsed openAIResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return Result{Attempts: 1}, fmt.Errorf("summarizer: decode openai response: %w", err)
+	}
+	if len(parsed.Choices) == 0 {
+		return Result{Attempts: 1}, fmt.Errorf("summarizer: openai response had no choices")
+	}
+
+	return Result{
+		Text:     parsed.Choices[0].Message.Content,
+		CostUsd:  estimateCost(openAIRates, s.model, parsed.Usage.PromptTokens, parsed.Usage.CompletionTokens),
+		Attempts: 1,
+	}, nil
+}
@@ -0,0 +1,42 @@
+// Package summarizer defines the Summarize contract runProcessor depends
+// on, so the LLM backend it talks to — the Pennsieve governor, a direct
+// provider HTTP client, or a local mock — can be swapped via configuration
+// without touching the sharding, retry, or PDF-generation code around it.
+package summarizer
+
+import "context"
+
+// Document is a single prompt to summarize: a name, the source format it
+// was decoded from, its canonical JSON (or, for a map-reduce reduce step,
+// plain text) payload, and the prompt and system instructions to send
+// alongside it.
+type Document struct {
+	Name      string
+	Format    string
+	Canonical []byte
+	Prompt    string
+	System    string
+}
+
+// Result is a single Summarize call's narrative text plus the accounting
+// runProcessor needs to report per-file cost and retry counts.
+type Result struct {
+	Text     string
+	CostUsd  float64
+	Attempts int
+}
+
+// Summarizer produces a narrative summary of a Document. Implementations
+// decide how (and whether) to retry, and how a Document's cost is
+// estimated.
+type Summarizer interface {
+	Summarize(ctx context.Context, doc Document) (Result, error)
+}
+
+// Availabler is an optional interface a Summarizer backend can implement
+// to report, cheaply and up front, whether it's configured to run — e.g.
+// the governor backend checks LLM_GOVERNOR_FUNCTION before any file is
+// processed rather than failing on the first document.
+type Availabler interface {
+	Available() bool
+}